@@ -0,0 +1,227 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+OverflowPolicy - Determines what happens when an async Logger's internal buffer is full.
+*/
+type OverflowPolicy string
+
+/*
+Overflow policies usable as LoggerConfig.OverflowPolicy.
+*/
+const (
+	Block      OverflowPolicy = "block"
+	DropOldest OverflowPolicy = "drop_oldest"
+	DropNewest OverflowPolicy = "drop_newest"
+)
+
+/*
+ErrFlushTimeout - Returned by Logger.Flush() if the buffer did not drain within the given timeout.
+*/
+var ErrFlushTimeout = errors.New("log: flush timed out before buffer drained")
+
+/*
+defaultAsyncBufferSize - Used when LoggerConfig.Async is true but BufferSize is unset.
+*/
+const defaultAsyncBufferSize = 256
+
+/*
+asyncDispatcher - Drains a buffered channel of LogEntry values on a background goroutine so that
+callers of printf/printLine/Event.Msg never block on a slow stream or hook, subject to the
+configured OverflowPolicy. A dispatcher may be shared by a Logger and the sub-loggers derived from
+it via NewModule/WithFields, so enqueue/close are safe to call concurrently and closing never
+panics a racing sender; once closed, writes are performed inline by the caller instead.
+*/
+type asyncDispatcher struct {
+	ch       chan LogEntry
+	policy   OverflowPolicy
+	writeFn  func(LogEntry)
+	dropped  int64
+	inFlight int64
+	closed   int32
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+/*
+newAsyncDispatcher - Starts the background drain goroutine, which calls writeFn for every entry.
+*/
+func newAsyncDispatcher(bufferSize int, policy OverflowPolicy, writeFn func(LogEntry)) *asyncDispatcher {
+	if bufferSize <= 0 {
+		bufferSize = defaultAsyncBufferSize
+	}
+
+	d := &asyncDispatcher{
+		ch:      make(chan LogEntry, bufferSize),
+		policy:  policy,
+		writeFn: writeFn,
+	}
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		for entry := range d.ch {
+			writeFn(entry)
+			atomic.AddInt64(&d.inFlight, -1)
+		}
+	}()
+
+	return d
+}
+
+/*
+enqueue - Hands an entry to the background goroutine, applying the configured OverflowPolicy if the
+buffer is currently full. If the dispatcher has been closed (e.g. by a sibling logger sharing it),
+the entry is written inline instead of being dropped.
+*/
+func (d *asyncDispatcher) enqueue(entry LogEntry) {
+	if atomic.LoadInt32(&d.closed) == 1 {
+		d.writeFn(entry)
+		return
+	}
+
+	atomic.AddInt64(&d.inFlight, 1)
+	if d.trySend(entry) {
+		return
+	}
+
+	switch d.policy {
+	case DropNewest:
+		d.dropOrWriteInline(entry)
+	case DropOldest:
+		select {
+		case <-d.ch:
+			atomic.AddInt64(&d.inFlight, -1)
+			atomic.AddInt64(&d.dropped, 1)
+		default:
+		}
+		if !d.trySend(entry) {
+			d.dropOrWriteInline(entry)
+		}
+	default: // Block
+		if !d.blockingSend(entry) {
+			atomic.AddInt64(&d.inFlight, -1)
+			d.writeFn(entry)
+		}
+	}
+}
+
+/*
+dropOrWriteInline - Called once trySend has failed under a Drop* policy. trySend's failure is
+ambiguous between "buffer full" (its select's default case) and "channel closed concurrently" (the
+send panics and its recover returns false), so this rechecks d.closed: if the dispatcher was actually closed out from under us,
+the entry is written inline rather than miscounted as a policy-driven drop.
+*/
+func (d *asyncDispatcher) dropOrWriteInline(entry LogEntry) {
+	atomic.AddInt64(&d.inFlight, -1)
+	if atomic.LoadInt32(&d.closed) == 1 {
+		d.writeFn(entry)
+		return
+	}
+	atomic.AddInt64(&d.dropped, 1)
+}
+
+/*
+trySend - Attempts a non-blocking send, recovering gracefully if the channel was closed
+concurrently by another sender's Close() call.
+*/
+func (d *asyncDispatcher) trySend(entry LogEntry) (sent bool) {
+	defer func() {
+		if recover() != nil {
+			sent = false
+		}
+	}()
+	select {
+	case d.ch <- entry:
+		return true
+	default:
+		return false
+	}
+}
+
+/*
+blockingSend - Attempts a blocking send, recovering gracefully if the channel was closed
+concurrently by another sender's Close() call.
+*/
+func (d *asyncDispatcher) blockingSend(entry LogEntry) (sent bool) {
+	defer func() {
+		if recover() != nil {
+			sent = false
+		}
+	}()
+	d.ch <- entry
+	return true
+}
+
+/*
+takeDropped - Returns and resets the count of entries dropped since it was last called.
+*/
+func (d *asyncDispatcher) takeDropped() int64 {
+	return atomic.SwapInt64(&d.dropped, 0)
+}
+
+/*
+flush - Blocks until the buffer has fully drained and every dequeued entry has finished writing, or
+timeout elapses first.
+*/
+func (d *asyncDispatcher) flush(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for atomic.LoadInt64(&d.inFlight) > 0 {
+		if time.Now().After(deadline) {
+			return ErrFlushTimeout
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return nil
+}
+
+/*
+close - Stops the background goroutine once its backlog has drained. Safe to call more than once,
+and safe to call while another logger sharing this dispatcher is still enqueueing: subsequent
+enqueue calls fall back to writing inline rather than panicking.
+*/
+func (d *asyncDispatcher) close() error {
+	d.once.Do(func() {
+		atomic.StoreInt32(&d.closed, 1)
+		close(d.ch)
+	})
+	d.wg.Wait()
+	return nil
+}
+
+/*
+Flush - Blocks until all buffered entries have been written, or returns ErrFlushTimeout if timeout
+elapses first. A no-op on a synchronous (non-Async) Logger.
+*/
+func (l *Logger) Flush(timeout time.Duration) error {
+	if l.async == nil {
+		return nil
+	}
+	return l.async.flush(timeout)
+}
+
+/*
+Close - Drains any buffered entries and stops the background dispatch goroutine. A no-op on a
+synchronous (non-Async) Logger. The Logger must not be used after Close returns.
+*/
+func (l *Logger) Close() error {
+	if l.async == nil {
+		return nil
+	}
+	return l.async.close()
+}
+
+/*
+dropSummaryMessage - Renders the message for a summary entry reporting entries silently dropped due
+to buffer overflow.
+*/
+func dropSummaryMessage(dropped int64) string {
+	return fmt.Sprintf("dropped %d log entries due to buffer overflow", dropped)
+}
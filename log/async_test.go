@@ -0,0 +1,213 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+/*
+blockingWriteFn - Returns a writeFn that signals started the first time it's called and then blocks
+until release is closed, recording every entry it eventually writes. Letting a test wait on started
+makes it deterministic that the background goroutine has pulled the first entry off the channel
+(and is therefore blocked rather than free to drain a second one) before the test enqueues more.
+*/
+func blockingWriteFn(release <-chan struct{}) (fn func(LogEntry), started <-chan struct{}, written *[]LogEntry, mu *sync.Mutex) {
+	var writtenSlice []LogEntry
+	var once sync.Once
+	var m sync.Mutex
+	startedCh := make(chan struct{})
+	fn = func(entry LogEntry) {
+		once.Do(func() { close(startedCh) })
+		<-release
+		m.Lock()
+		writtenSlice = append(writtenSlice, entry)
+		m.Unlock()
+	}
+	return fn, startedCh, &writtenSlice, &m
+}
+
+/*
+TestAsyncDispatcherDropNewest - Once the buffer is full, DropNewest must discard the entry being
+enqueued and leave everything already buffered untouched.
+*/
+func TestAsyncDispatcherDropNewest(t *testing.T) {
+	release := make(chan struct{})
+	writeFn, started, written, mu := blockingWriteFn(release)
+
+	d := newAsyncDispatcher(1, DropNewest, writeFn)
+	d.enqueue(LogEntry{Message: "first"}) // taken by the goroutine, blocks on release
+	<-started
+	d.enqueue(LogEntry{Message: "second"}) // fills the buffer
+	d.enqueue(LogEntry{Message: "third"})  // buffer full, dropped
+
+	if dropped := d.takeDropped(); dropped != 1 {
+		t.Fatalf("expected 1 dropped entry, got %d", dropped)
+	}
+
+	close(release)
+	if err := d.flush(time.Second); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if err := d.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(*written) != 2 {
+		t.Fatalf("expected 2 entries written, got %d: %v", len(*written), *written)
+	}
+	if (*written)[1].Message != "second" {
+		t.Fatalf("expected second entry to be %q, got %q", "second", (*written)[1].Message)
+	}
+}
+
+/*
+TestAsyncDispatcherDropOldest - Once the buffer is full, DropOldest must evict the oldest buffered
+entry to make room for the one being enqueued.
+*/
+func TestAsyncDispatcherDropOldest(t *testing.T) {
+	release := make(chan struct{})
+	writeFn, started, written, mu := blockingWriteFn(release)
+
+	d := newAsyncDispatcher(1, DropOldest, writeFn)
+	d.enqueue(LogEntry{Message: "first"}) // taken by the goroutine, blocks on release
+	<-started
+	d.enqueue(LogEntry{Message: "second"}) // fills the buffer
+	d.enqueue(LogEntry{Message: "third"})  // evicts "second", takes its place
+
+	if dropped := d.takeDropped(); dropped != 1 {
+		t.Fatalf("expected 1 dropped entry, got %d", dropped)
+	}
+
+	close(release)
+	if err := d.flush(time.Second); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if err := d.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(*written) != 2 {
+		t.Fatalf("expected 2 entries written, got %d: %v", len(*written), *written)
+	}
+	if (*written)[1].Message != "third" {
+		t.Fatalf("expected second entry to be %q, got %q", "third", (*written)[1].Message)
+	}
+}
+
+/*
+TestAsyncDispatcherBlock - Under the Block policy, enqueue must deliver every entry rather than
+dropping any, even once the buffer has filled up.
+*/
+func TestAsyncDispatcherBlock(t *testing.T) {
+	var mu sync.Mutex
+	var written []LogEntry
+	writeFn := func(entry LogEntry) {
+		mu.Lock()
+		written = append(written, entry)
+		mu.Unlock()
+	}
+
+	d := newAsyncDispatcher(1, Block, writeFn)
+	for i := 0; i < 5; i++ {
+		d.enqueue(LogEntry{Message: "entry"})
+	}
+
+	if err := d.flush(time.Second); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if err := d.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(written) != 5 {
+		t.Fatalf("expected 5 entries written, got %d", len(written))
+	}
+	if dropped := d.takeDropped(); dropped != 0 {
+		t.Fatalf("expected no drops under Block policy, got %d", dropped)
+	}
+}
+
+/*
+TestAsyncDispatcherCloseDoesNotPanicConcurrentEnqueue - Closing a dispatcher while another goroutine
+is still enqueueing onto it (the shared-dispatcher scenario between a Logger and its submodules) must
+fall back to writing inline rather than panicking on a send to a closed channel.
+*/
+func TestAsyncDispatcherCloseDoesNotPanicConcurrentEnqueue(t *testing.T) {
+	var mu sync.Mutex
+	var written []LogEntry
+	writeFn := func(entry LogEntry) {
+		mu.Lock()
+		written = append(written, entry)
+		mu.Unlock()
+	}
+
+	d := newAsyncDispatcher(4, Block, writeFn)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			d.enqueue(LogEntry{Message: "entry"})
+		}
+	}()
+
+	if err := d.close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	wg.Wait()
+}
+
+/*
+TestDropOrWriteInlineWritesWhenClosed - Once a Drop* dispatcher has been closed, a failed trySend must
+be treated as a closed-channel race rather than a policy-driven drop: dropOrWriteInline must write the
+entry inline and must not count it against dropped.
+*/
+func TestDropOrWriteInlineWritesWhenClosed(t *testing.T) {
+	var written []LogEntry
+	d := newAsyncDispatcher(1, DropNewest, func(entry LogEntry) {
+		written = append(written, entry)
+	})
+	defer d.close()
+
+	atomic.StoreInt32(&d.closed, 1)
+	atomic.AddInt64(&d.inFlight, 1)
+	d.dropOrWriteInline(LogEntry{Message: "late"})
+
+	if len(written) != 1 || written[0].Message != "late" {
+		t.Fatalf("expected entry to be written inline, got %v", written)
+	}
+	if dropped := d.takeDropped(); dropped != 0 {
+		t.Fatalf("expected no drop to be recorded for a closed-channel race, got %d", dropped)
+	}
+}
+
+/*
+TestDropOrWriteInlineDropsWhenStillOpen - While the dispatcher is still open, a failed trySend really
+does mean the buffer was full, so dropOrWriteInline must record a drop rather than writing inline.
+*/
+func TestDropOrWriteInlineDropsWhenStillOpen(t *testing.T) {
+	var written []LogEntry
+	d := newAsyncDispatcher(1, DropNewest, func(entry LogEntry) {
+		written = append(written, entry)
+	})
+	defer d.close()
+
+	atomic.AddInt64(&d.inFlight, 1)
+	d.dropOrWriteInline(LogEntry{Message: "overflow"})
+
+	if len(written) != 0 {
+		t.Fatalf("expected nothing written inline while still open, got %v", written)
+	}
+	if dropped := d.takeDropped(); dropped != 1 {
+		t.Fatalf("expected 1 drop recorded, got %d", dropped)
+	}
+}
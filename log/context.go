@@ -0,0 +1,126 @@
+package log
+
+import "context"
+
+/*
+ctxKey - The type used for the context key under which a Logger is stored, kept unexported so it
+can't collide with keys from other packages.
+*/
+type ctxKey int
+
+const loggerCtxKey ctxKey = iota
+
+/*
+ContextField - A well-known context key that the *Ctx logging helpers look for and, if present,
+automatically attach to the emitted entry.
+*/
+type ContextField string
+
+/*
+Well-known context fields picked up automatically by the *Ctx logging helpers.
+*/
+const (
+	RequestIDField ContextField = "request_id"
+	TraceIDField   ContextField = "trace_id"
+	UserIDField    ContextField = "user_id"
+)
+
+/*
+NewContext - Returns a new Context carrying the given Logger, retrievable via FromContext.
+*/
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerCtxKey, logger)
+}
+
+/*
+FromContext - Returns the Logger previously attached to ctx via NewContext, or nil if none was set.
+*/
+func FromContext(ctx context.Context) *Logger {
+	logger, _ := ctx.Value(loggerCtxKey).(*Logger)
+	return logger
+}
+
+/*
+ctxFields - Extracts any well-known identifiers present on ctx (request ID, trace ID, user ID) as
+log fields.
+*/
+func ctxFields(ctx context.Context) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for _, key := range [...]ContextField{RequestIDField, TraceIDField, UserIDField} {
+		if v := ctx.Value(key); v != nil {
+			fields[string(key)] = v
+		}
+	}
+	return fields
+}
+
+/*
+mergedCtxFields - Combines the logger's sticky fields with any well-known identifiers found on ctx,
+favouring ctx values on key collision.
+*/
+func (l *Logger) mergedCtxFields(ctx context.Context) map[string]interface{} {
+	extracted := ctxFields(ctx)
+	if len(l.fields) == 0 {
+		return extracted
+	}
+
+	merged := make(map[string]interface{}, len(l.fields)+len(extracted))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range extracted {
+		merged[k] = v
+	}
+	return merged
+}
+
+/*--------------------------------------------------------------------------------------------------
+ */
+
+/*
+FatalfCtx - Print a fatal message to the console, annotated with any well-known identifiers found on
+ctx. Does NOT cause panic.
+*/
+func (l *Logger) FatalfCtx(ctx context.Context, message string, other ...interface{}) {
+	l.emitf(LogFatal, "FATAL", message, l.mergedCtxFields(ctx), other...)
+}
+
+/*
+ErrorfCtx - Print an error message to the console, annotated with any well-known identifiers found
+on ctx.
+*/
+func (l *Logger) ErrorfCtx(ctx context.Context, message string, other ...interface{}) {
+	l.emitf(LogError, "ERROR", message, l.mergedCtxFields(ctx), other...)
+}
+
+/*
+WarnfCtx - Print a warning message to the console, annotated with any well-known identifiers found
+on ctx.
+*/
+func (l *Logger) WarnfCtx(ctx context.Context, message string, other ...interface{}) {
+	l.emitf(LogWarn, "WARN", message, l.mergedCtxFields(ctx), other...)
+}
+
+/*
+InfofCtx - Print an information message to the console, annotated with any well-known identifiers
+found on ctx.
+*/
+func (l *Logger) InfofCtx(ctx context.Context, message string, other ...interface{}) {
+	l.emitf(LogInfo, "INFO", message, l.mergedCtxFields(ctx), other...)
+}
+
+/*
+DebugfCtx - Print a debug message to the console, annotated with any well-known identifiers found on
+ctx.
+*/
+func (l *Logger) DebugfCtx(ctx context.Context, message string, other ...interface{}) {
+	l.emitf(LogDebug, "DEBUG", message, l.mergedCtxFields(ctx), other...)
+}
+
+/*
+TracefCtx - Print a trace message to the console, annotated with any well-known identifiers found on
+ctx.
+*/
+func (l *Logger) TracefCtx(ctx context.Context, message string, other ...interface{}) {
+	l.emitf(LogTrace, "TRACE", message, l.mergedCtxFields(ctx), other...)
+}
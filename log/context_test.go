@@ -0,0 +1,82 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+/*
+TestNewContextFromContext - A Logger attached via NewContext must be retrievable via FromContext, and
+FromContext on a context with none attached must return nil.
+*/
+func TestNewContextFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != nil {
+		t.Fatalf("expected nil for a context with no logger attached, got %v", got)
+	}
+
+	logger := NewLogger(&bytes.Buffer{}, LoggerConfig{LogLevel: "INFO"})
+	ctx := NewContext(context.Background(), logger)
+
+	if got := FromContext(ctx); got != logger {
+		t.Fatalf("expected FromContext to return the attached logger, got %v", got)
+	}
+}
+
+/*
+TestCtxFieldsExtractsWellKnownKeys - Only the well-known ContextField keys present on ctx should be
+extracted; anything else on the context must be ignored.
+*/
+func TestCtxFieldsExtractsWellKnownKeys(t *testing.T) {
+	ctx := context.WithValue(context.Background(), RequestIDField, "req-1")
+	ctx = context.WithValue(ctx, TraceIDField, "trace-1")
+
+	fields := ctxFields(ctx)
+
+	if fields[string(RequestIDField)] != "req-1" || fields[string(TraceIDField)] != "trace-1" {
+		t.Fatalf("unexpected fields: %+v", fields)
+	}
+	if _, ok := fields[string(UserIDField)]; ok {
+		t.Fatalf("expected no user_id field when none was set, got %+v", fields)
+	}
+}
+
+/*
+TestMergedCtxFieldsCtxWins - mergedCtxFields must favour a well-known ctx value over a sticky field of
+the same key already accumulated on the logger.
+*/
+func TestMergedCtxFieldsCtxWins(t *testing.T) {
+	logger := NewLogger(&bytes.Buffer{}, LoggerConfig{LogLevel: "INFO"}).WithFields(map[string]interface{}{
+		string(RequestIDField): "sticky",
+		"component":            "worker",
+	})
+
+	ctx := context.WithValue(context.Background(), RequestIDField, "from-ctx")
+	merged := logger.mergedCtxFields(ctx)
+
+	if merged[string(RequestIDField)] != "from-ctx" {
+		t.Fatalf("expected ctx value to win, got %+v", merged)
+	}
+	if merged["component"] != "worker" {
+		t.Fatalf("expected sticky field to survive the merge, got %+v", merged)
+	}
+}
+
+/*
+TestErrorfCtxAnnotatesWithContextFields - *Ctx helpers must fold well-known ctx fields into the
+emitted entry.
+*/
+func TestErrorfCtxAnnotatesWithContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, LoggerConfig{LogLevel: "ERROR", Format: "json", AddTimeStamp: false})
+
+	ctx := context.WithValue(context.Background(), RequestIDField, "req-42")
+	logger.ErrorfCtx(ctx, "failed after %d attempts", 3)
+
+	out := buf.String()
+	for _, want := range []string{`"request_id":"req-42"`, `"message":"failed after 3 attempts"`} {
+		if !bytes.Contains([]byte(out), []byte(want)) {
+			t.Fatalf("expected output to contain %q, got %q", want, out)
+		}
+	}
+}
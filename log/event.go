@@ -0,0 +1,64 @@
+package log
+
+/*
+Event - A chainable, structured log entry built up via Logger.With(). Key/value pairs accumulated
+through Str, Int, Err and Interface are emitted as a single JSON object (or folded into the text
+line) when Msg or Msgf is called.
+*/
+type Event struct {
+	logger *Logger
+	level  int
+	fields map[string]interface{}
+}
+
+/*
+Str - Adds a string field to the event.
+*/
+func (e *Event) Str(key, val string) *Event {
+	e.fields[key] = val
+	return e
+}
+
+/*
+Int - Adds an integer field to the event.
+*/
+func (e *Event) Int(key string, val int) *Event {
+	e.fields[key] = val
+	return e
+}
+
+/*
+Err - Adds the error as an "error" field on the event. A nil error is a no-op.
+*/
+func (e *Event) Err(err error) *Event {
+	if err != nil {
+		e.fields["error"] = err.Error()
+	}
+	return e
+}
+
+/*
+Interface - Adds a field of arbitrary type to the event.
+*/
+func (e *Event) Interface(key string, v interface{}) *Event {
+	e.fields[key] = v
+	return e
+}
+
+/*
+Msg - Emits the event with the given message, respecting the logger's level threshold and the
+registered formatter and hooks.
+*/
+func (e *Event) Msg(message string) {
+	e.logger.emit(e.level, intToLogLevel(e.level), message, e.fields)
+}
+
+/*
+Msgf - Formats the message and emits the event, as per Msg, but deduplicates on format (the
+pre-interpolation template) rather than the interpolated text, so that calls with the same template
+and varying arguments are recognised as duplicates. Formatting is deferred until after the level
+threshold check, same as Logger.emitf, so a disabled level never pays for Sprintf.
+*/
+func (e *Event) Msgf(format string, args ...interface{}) {
+	e.logger.emitf(e.level, intToLogLevel(e.level), format, e.fields, args...)
+}
@@ -0,0 +1,83 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+TestEventFieldChaining - Str, Int, Err and Interface must accumulate onto the same Event, and a nil
+error passed to Err must be a no-op.
+*/
+func TestEventFieldChaining(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, LoggerConfig{LogLevel: "INFO", Format: "json"})
+
+	logger.With().
+		Str("component", "worker").
+		Int("attempt", 3).
+		Err(errors.New("boom")).
+		Err(nil).
+		Interface("extra", 7).
+		Msg("failed")
+
+	out := buf.String()
+	for _, want := range []string{`"component":"worker"`, `"attempt":3`, `"error":"boom"`, `"extra":7`, `"message":"failed"`} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected output to contain %q, got %q", want, out)
+		}
+	}
+}
+
+/*
+TestEventMsgfDoesNotFormatBelowThreshold - Msgf must not invoke fmt.Sprintf on its arguments at all
+when the event's level is below the logger's configured threshold, matching Logger.emitf.
+*/
+func TestEventMsgfDoesNotFormatBelowThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, LoggerConfig{LogLevel: "ERROR"})
+
+	called := false
+	logger.With().Msgf("value is %v", stringerFunc(func() string {
+		called = true
+		return "x"
+	}))
+
+	if called {
+		t.Fatal("expected Stringer not to be invoked for a disabled level")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing written, got %q", buf.String())
+	}
+}
+
+/*
+TestEventMsgfDedupesOnFormat - Msgf must key its dedup window on the format string, not the
+interpolated text, same as Logger.emitf.
+*/
+func TestEventMsgfDedupesOnFormat(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, LoggerConfig{
+		LogLevel:  "INFO",
+		RateLimit: RateLimitConfig{DedupWindow: time.Hour},
+	})
+
+	for i := 0; i < 5; i++ {
+		logger.With().Msgf("request to %v failed", i)
+	}
+
+	lines := countLines(buf.String())
+	if lines != 1 {
+		t.Fatalf("expected exactly 1 line emitted, got %d: %q", lines, buf.String())
+	}
+}
+
+func countLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	return len(strings.Split(strings.TrimRight(s, "\n"), "\n"))
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+/*
+LogEntry - A single structured log record passed to a Formatter and any registered Hooks.
+*/
+type LogEntry struct {
+	Level     string
+	Prefix    string
+	Timestamp time.Time
+	Message   string
+	Fields    map[string]interface{}
+}
+
+/*
+Formatter - Renders a LogEntry into bytes ready to be written to a Logger's stream.
+*/
+type Formatter interface {
+	Format(entry LogEntry) []byte
+}
+
+/*
+TextFormatter - Renders entries as the traditional "timestamp | level | prefix | message" line,
+appending any fields as trailing "key=value" pairs.
+*/
+type TextFormatter struct {
+	AddTimeStamp bool
+}
+
+/*
+Format - Implements Formatter.
+*/
+func (f *TextFormatter) Format(entry LogEntry) []byte {
+	timestampStr := ""
+	if f.AddTimeStamp {
+		timestampStr = fmt.Sprintf("%v | ", entry.Timestamp.Format(time.RFC3339))
+	}
+
+	line := fmt.Sprintf("%v%v | %v | %v", timestampStr, entry.Level, entry.Prefix, entry.Message)
+	for k, v := range entry.Fields {
+		line = fmt.Sprintf("%v %v=%v", line, k, v)
+	}
+	return []byte(line + "\n")
+}
+
+/*
+JSONFormatter - Renders entries as a single JSON object per line.
+*/
+type JSONFormatter struct {
+	AddTimeStamp bool
+}
+
+/*
+Format - Implements Formatter.
+*/
+func (f *JSONFormatter) Format(entry LogEntry) []byte {
+	obj := make(map[string]interface{}, len(entry.Fields)+4)
+	for k, v := range entry.Fields {
+		obj[k] = v
+	}
+	obj["level"] = entry.Level
+	obj["prefix"] = entry.Prefix
+	obj["message"] = entry.Message
+	if f.AddTimeStamp {
+		obj["timestamp"] = entry.Timestamp.Format(time.RFC3339)
+	}
+
+	line, err := json.Marshal(obj)
+	if err != nil {
+		return []byte(fmt.Sprintf("{\"level\":\"ERROR\",\"message\":\"failed to marshal log entry: %v\"}\n", err))
+	}
+	return append(line, '\n')
+}
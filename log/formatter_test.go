@@ -0,0 +1,93 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+TestTextFormatterFormat - The rendered line must contain the level, prefix, message and each field
+as a trailing key=value pair, and must omit the timestamp when AddTimeStamp is false.
+*/
+func TestTextFormatterFormat(t *testing.T) {
+	f := &TextFormatter{AddTimeStamp: false}
+	entry := LogEntry{
+		Level:   "INFO",
+		Prefix:  "service",
+		Message: "hello",
+		Fields:  map[string]interface{}{"attempt": 2},
+	}
+
+	line := string(f.Format(entry))
+
+	for _, want := range []string{"INFO", "service", "hello", "attempt=2"} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected line to contain %q, got %q", want, line)
+		}
+	}
+	if !strings.HasSuffix(line, "\n") {
+		t.Fatalf("expected line to end with a newline, got %q", line)
+	}
+}
+
+/*
+TestTextFormatterAddTimeStamp - With AddTimeStamp true, the rendered line must lead with the entry's
+timestamp in RFC3339.
+*/
+func TestTextFormatterAddTimeStamp(t *testing.T) {
+	f := &TextFormatter{AddTimeStamp: true}
+	ts := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+	line := string(f.Format(LogEntry{Level: "INFO", Prefix: "p", Message: "m", Timestamp: ts}))
+
+	if !strings.HasPrefix(line, ts.Format(time.RFC3339)) {
+		t.Fatalf("expected line to start with the formatted timestamp, got %q", line)
+	}
+}
+
+/*
+TestJSONFormatterFormat - The rendered line must be a single valid JSON object carrying level,
+prefix, message and every field as top-level keys.
+*/
+func TestJSONFormatterFormat(t *testing.T) {
+	f := &JSONFormatter{AddTimeStamp: false}
+	entry := LogEntry{
+		Level:   "ERROR",
+		Prefix:  "service",
+		Message: "boom",
+		Fields:  map[string]interface{}{"attempt": float64(2)},
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(f.Format(entry), &obj); err != nil {
+		t.Fatalf("expected valid JSON, got error: %v, line: %q", err, f.Format(entry))
+	}
+
+	if obj["level"] != "ERROR" || obj["prefix"] != "service" || obj["message"] != "boom" {
+		t.Fatalf("unexpected base fields: %+v", obj)
+	}
+	if obj["attempt"] != float64(2) {
+		t.Fatalf("expected attempt field to round-trip, got %+v", obj)
+	}
+	if _, ok := obj["timestamp"]; ok {
+		t.Fatalf("expected no timestamp key when AddTimeStamp is false, got %+v", obj)
+	}
+}
+
+/*
+TestJSONFormatterAddTimeStamp - With AddTimeStamp true, the rendered object must carry a "timestamp"
+key formatted as RFC3339.
+*/
+func TestJSONFormatterAddTimeStamp(t *testing.T) {
+	f := &JSONFormatter{AddTimeStamp: true}
+	ts := time.Date(2026, 7, 26, 12, 0, 0, 0, time.UTC)
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(f.Format(LogEntry{Timestamp: ts}), &obj); err != nil {
+		t.Fatalf("expected valid JSON: %v", err)
+	}
+	if obj["timestamp"] != ts.Format(time.RFC3339) {
+		t.Fatalf("expected timestamp %q, got %+v", ts.Format(time.RFC3339), obj["timestamp"])
+	}
+}
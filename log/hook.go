@@ -0,0 +1,65 @@
+package log
+
+import "fmt"
+
+/*
+Hook - Receives a copy of every LogEntry that passes the logger's level threshold, for side effects
+such as forwarding to Riemann, syslog, a file, or a metrics counter.
+*/
+type Hook interface {
+	Fire(entry LogEntry) error
+}
+
+/*
+RiemannHook - Forwards log entries to a Riemann service, promoting a "metric" field to
+RiemannEvent.Metric and flattening the remaining fields into Attributes.
+*/
+type RiemannHook struct {
+	Client *RiemannClient
+}
+
+/*
+Fire - Implements Hook.
+*/
+func (h *RiemannHook) Fire(entry LogEntry) error {
+	if h.Client == nil {
+		return ErrClientNil
+	}
+	return h.Client.SendEvent(buildRiemannEvent(entry))
+}
+
+/*
+buildRiemannEvent - Translates a LogEntry into a RiemannEvent, promoting a "metric" field to
+RiemannEvent.Metric and flattening the remaining fields into Attributes. Split out from Fire so the
+promotion logic can be exercised without a real RiemannClient.
+*/
+func buildRiemannEvent(entry LogEntry) RiemannEvent {
+	event := RiemannEvent{
+		Service:     entry.Prefix,
+		State:       entry.Level,
+		Description: entry.Message,
+	}
+
+	if len(entry.Fields) > 0 {
+		attributes := make(map[string]string, len(entry.Fields))
+		for k, v := range entry.Fields {
+			if k == "metric" {
+				switch m := v.(type) {
+				case float64:
+					event.Metric = m
+				case int:
+					event.Metric = float64(m)
+				default:
+					attributes[k] = fmt.Sprintf("%v", v)
+				}
+				continue
+			}
+			attributes[k] = fmt.Sprintf("%v", v)
+		}
+		if len(attributes) > 0 {
+			event.Attributes = attributes
+		}
+	}
+
+	return event
+}
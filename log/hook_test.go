@@ -0,0 +1,76 @@
+package log
+
+import "testing"
+
+/*
+TestRiemannHookFireNilClient - Fire must return ErrClientNil rather than panicking when no
+RiemannClient has been configured.
+*/
+func TestRiemannHookFireNilClient(t *testing.T) {
+	hook := &RiemannHook{}
+	if err := hook.Fire(LogEntry{}); err != ErrClientNil {
+		t.Fatalf("expected ErrClientNil, got %v", err)
+	}
+}
+
+/*
+TestBuildRiemannEventPromotesMetric - A numeric "metric" field must be promoted to RiemannEvent.Metric
+rather than flattened into Attributes, for both the float64 and int cases.
+*/
+func TestBuildRiemannEventPromotesMetric(t *testing.T) {
+	entry := LogEntry{
+		Prefix:  "service",
+		Level:   "INFO",
+		Message: "request handled",
+		Fields: map[string]interface{}{
+			"metric":   42,
+			"endpoint": "/health",
+		},
+	}
+
+	event := buildRiemannEvent(entry)
+
+	if event.Service != "service" || event.State != "INFO" || event.Description != "request handled" {
+		t.Fatalf("unexpected base fields: %+v", event)
+	}
+	if event.Metric != 42 {
+		t.Fatalf("expected metric 42, got %v", event.Metric)
+	}
+	if _, ok := event.Attributes["metric"]; ok {
+		t.Fatal("metric field must not also appear in Attributes")
+	}
+	if event.Attributes["endpoint"] != "/health" {
+		t.Fatalf("expected endpoint attribute, got %+v", event.Attributes)
+	}
+}
+
+/*
+TestBuildRiemannEventNonNumericMetricFallsIntoAttributes - A "metric" field that isn't a float64 or
+int isn't a valid RiemannEvent.Metric, so it must fall back to being flattened into Attributes like
+any other field.
+*/
+func TestBuildRiemannEventNonNumericMetricFallsIntoAttributes(t *testing.T) {
+	entry := LogEntry{
+		Fields: map[string]interface{}{"metric": "not-a-number"},
+	}
+
+	event := buildRiemannEvent(entry)
+
+	if event.Metric != 0 {
+		t.Fatalf("expected Metric to stay zero, got %v", event.Metric)
+	}
+	if event.Attributes["metric"] != "not-a-number" {
+		t.Fatalf("expected non-numeric metric to flatten into Attributes, got %+v", event.Attributes)
+	}
+}
+
+/*
+TestBuildRiemannEventNoFieldsLeavesAttributesNil - With no fields at all, Attributes must stay nil
+rather than being allocated as an empty map.
+*/
+func TestBuildRiemannEventNoFieldsLeavesAttributesNil(t *testing.T) {
+	event := buildRiemannEvent(LogEntry{Prefix: "service", Level: "INFO", Message: "ping"})
+	if event.Attributes != nil {
+		t.Fatalf("expected nil Attributes, got %+v", event.Attributes)
+	}
+}
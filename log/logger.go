@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -104,9 +105,14 @@ func logLevelToInt(level string) int {
 LoggerConfig - Holds configuration options for a logger object.
 */
 type LoggerConfig struct {
-	Prefix       string `json:"prefix" yaml:"prefix"`
-	LogLevel     string `json:"log_level" yaml:"log_level"`
-	AddTimeStamp bool   `json:"add_timestamp" yaml:"add_timestamp"`
+	Prefix         string          `json:"prefix" yaml:"prefix"`
+	LogLevel       string          `json:"log_level" yaml:"log_level"`
+	AddTimeStamp   bool            `json:"add_timestamp" yaml:"add_timestamp"`
+	Format         string          `json:"format" yaml:"format"`
+	RateLimit      RateLimitConfig `json:"rate_limit" yaml:"rate_limit"`
+	Async          bool            `json:"async" yaml:"async"`
+	BufferSize     int             `json:"buffer_size" yaml:"buffer_size"`
+	OverflowPolicy OverflowPolicy  `json:"overflow_policy" yaml:"overflow_policy"`
 }
 
 /*
@@ -118,6 +124,7 @@ func DefaultLoggerConfig() LoggerConfig {
 		Prefix:       "service",
 		LogLevel:     "INFO",
 		AddTimeStamp: true,
+		Format:       "text",
 	}
 }
 
@@ -128,10 +135,15 @@ func DefaultLoggerConfig() LoggerConfig {
 Logger - A logger object with support for levelled logging and modular components.
 */
 type Logger struct {
-	stream        io.Writer
-	config        LoggerConfig
-	level         int
-	riemannClient *RiemannClient
+	stream    io.Writer
+	config    LoggerConfig
+	level     int
+	formatter Formatter
+	hooks     []Hook
+	fields    map[string]interface{}
+	limiter   *rateLimiter
+	async     *asyncDispatcher
+	writeMu   *sync.Mutex
 }
 
 /*
@@ -139,78 +151,255 @@ NewLogger - Create and return a new logger object.
 */
 func NewLogger(stream io.Writer, config LoggerConfig) *Logger {
 	logger := Logger{
-		stream: stream,
-		config: config,
-		level:  logLevelToInt(config.LogLevel),
+		stream:    stream,
+		config:    config,
+		level:     logLevelToInt(config.LogLevel),
+		formatter: formatterForConfig(config),
+		limiter:   newRateLimiter(config.RateLimit),
+		writeMu:   &sync.Mutex{},
+	}
+	if config.Async {
+		logger.async = newAsyncDispatcher(config.BufferSize, config.OverflowPolicy, logger.writeEntry)
 	}
 	return &logger
 }
 
+/*
+formatterForConfig - Returns the default Formatter implied by a LoggerConfig's Format field.
+*/
+func formatterForConfig(config LoggerConfig) Formatter {
+	if config.Format == "json" {
+		return &JSONFormatter{AddTimeStamp: config.AddTimeStamp}
+	}
+	return &TextFormatter{AddTimeStamp: config.AddTimeStamp}
+}
+
 /*
 NewModule - Creates a new logger object from the previous, using the same configuration, but adds
-an extra prefix to represent a submodule.
+an extra prefix to represent a submodule. Any sticky fields attached through WithFields(), along with the
+formatter, hooks, and rate limiter/dedup state, are inherited by the returned sub-logger, since
+submodules write to the same underlying stream and Riemann client the limiter is there to protect.
 */
 func (l *Logger) NewModule(prefix string) *Logger {
 	config := l.config
 	config.Prefix = fmt.Sprintf("%v%v", config.Prefix, prefix)
 
+	var fields map[string]interface{}
+	if len(l.fields) > 0 {
+		fields = make(map[string]interface{}, len(l.fields))
+		for k, v := range l.fields {
+			fields[k] = v
+		}
+	}
+
+	hooks := make([]Hook, len(l.hooks))
+	copy(hooks, l.hooks)
+
 	return &Logger{
-		stream:        l.stream,
-		config:        config,
-		level:         l.level,
-		riemannClient: l.riemannClient,
+		stream:    l.stream,
+		config:    config,
+		level:     l.level,
+		formatter: l.formatter,
+		hooks:     hooks,
+		fields:    fields,
+		limiter:   l.limiter,
+		async:     l.async,
+		writeMu:   l.writeMu,
 	}
 }
 
 /*
-UseRiemann - Register a RiemannClient object to be used for pushing log events to a riemann service.
+UseRiemann - Register a RiemannClient object to be used for pushing log events to a riemann service,
+by wrapping it in a RiemannHook and registering it via AddHook.
 */
 func (l *Logger) UseRiemann(client *RiemannClient) error {
 	if client == nil {
 		return ErrClientNil
 	}
-	l.riemannClient = client
+	l.AddHook(&RiemannHook{Client: client})
 	return nil
 }
 
+/*
+SetFormatter - Overrides the Formatter used to render log entries written to the stream.
+*/
+func (l *Logger) SetFormatter(formatter Formatter) {
+	l.formatter = formatter
+}
+
+/*
+AddHook - Registers a Hook to receive every LogEntry that passes the logger's level threshold.
+*/
+func (l *Logger) AddHook(hook Hook) {
+	l.hooks = append(l.hooks, hook)
+}
+
+/*
+With - Begins a structured log entry at info level, returning an Event that accumulated key/value
+pairs can be chained onto before being emitted with Msg()/Msgf().
+*/
+func (l *Logger) With() *Event {
+	fields := make(map[string]interface{}, len(l.fields))
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	return &Event{logger: l, level: LogInfo, fields: fields}
+}
+
+/*
+WithFields - Returns a child logger carrying the given fields in addition to any already
+accumulated, without allocating a new prefix string. Every subsequent log line emitted through the
+returned logger carries these fields, making it convenient for e.g. HTTP middleware to attach
+per-request context once.
+*/
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return &Logger{
+		stream:    l.stream,
+		config:    l.config,
+		level:     l.level,
+		formatter: l.formatter,
+		hooks:     l.hooks,
+		fields:    merged,
+		limiter:   l.limiter,
+		async:     l.async,
+		writeMu:   l.writeMu,
+	}
+}
+
 /*--------------------------------------------------------------------------------------------------
  */
 
 /*
-printf - Prints a log message with any configured extras prepended.
+emit - Builds a LogEntry and runs it through the formatter and hook pipeline, short-circuiting
+before allocating anything if the level is below the logger's configured threshold.
 */
-func (l *Logger) printf(message, level string, other ...interface{}) {
-	timestampStr := ""
-	if l.config.AddTimeStamp {
-		timestampStr = fmt.Sprintf("%v | ", time.Now().Format(time.RFC3339))
-	}
+func (l *Logger) emit(levelInt int, level, message string, fields map[string]interface{}) {
+	l.dispatch(levelInt, level, message, message, fields)
+}
 
-	fmt.Fprintf(l.stream, fmt.Sprintf("%v%v | %v | %v", timestampStr, level, l.config.Prefix, message), other...)
+/*
+emitf - Formats message with other before emitting, but deduplicates on message itself (the
+pre-interpolation format string) rather than the interpolated text, so that e.g. repeated
+Errorf("dial tcp %d: refused", port) calls with varying ports are recognised as duplicates. The
+Sprintf is deferred behind the level check so that a disabled level never pays for argument
+formatting (which may itself invoke a Stringer/Error with real cost or side effects).
+*/
+func (l *Logger) emitf(levelInt int, level, message string, fields map[string]interface{}, other ...interface{}) {
+	if levelInt > l.level {
+		return
+	}
+	l.dispatch(levelInt, level, message, fmt.Sprintf(message, other...), fields)
 }
 
 /*
-printLine - Prints a log message with any configured extras prepended.
+dispatch - Runs dedupKey through rate limiting/deduplication (if configured) before emitting text as
+the entry's message, short-circuiting before allocating anything if the level is below the logger's
+configured threshold.
 */
-func (l *Logger) printLine(message, level string) {
-	timestampStr := ""
-	if l.config.AddTimeStamp {
-		timestampStr = fmt.Sprintf("%v | ", time.Now().Format(time.RFC3339))
+func (l *Logger) dispatch(levelInt int, level, dedupKey, text string, fields map[string]interface{}) {
+	if levelInt > l.level {
+		return
+	}
+
+	if l.limiter != nil {
+		summary, hasSummary, pass := l.limiter.dedupCheck(levelInt, level, dedupKey, text)
+		if hasSummary {
+			l.emitRaw(levelInt, level, summary, nil)
+		}
+		if !pass {
+			l.limiter.recordDropped(levelInt)
+			return
+		}
+		if !l.limiter.allowRate(levelInt) {
+			l.limiter.recordDropped(levelInt)
+			return
+		}
 	}
 
-	fmt.Fprintf(l.stream, fmt.Sprintf("%v%v | %v | %v\n", timestampStr, level, l.config.Prefix, message))
+	l.emitRaw(levelInt, level, text, fields)
 }
 
 /*
-sendRiemann - If a Riemann client has been set then we send a log event through it.
+emitRaw - Builds a LogEntry and runs it through the formatter and hook pipeline, bypassing rate
+limiting and deduplication. Used both for ordinary entries and for dedup summary lines.
 */
-func (l *Logger) sendRiemann(message, level string, other ...interface{}) {
-	if l.riemannClient != nil {
-		l.riemannClient.SendEvent(RiemannEvent{
-			Service:     l.config.Prefix,
-			State:       level,
-			Description: fmt.Sprintf(message, other...),
+func (l *Logger) emitRaw(levelInt int, level, message string, fields map[string]interface{}) {
+	if l.limiter != nil {
+		l.limiter.recordEmitted(levelInt)
+	}
+
+	entry := LogEntry{
+		Level:     level,
+		Prefix:    l.config.Prefix,
+		Timestamp: time.Now(),
+		Message:   message,
+		Fields:    fields,
+	}
+
+	if l.async == nil || levelInt == LogFatal {
+		l.writeEntry(entry)
+		return
+	}
+
+	if dropped := l.async.takeDropped(); dropped > 0 {
+		l.async.enqueue(LogEntry{
+			Level:     level,
+			Prefix:    l.config.Prefix,
+			Timestamp: time.Now(),
+			Message:   dropSummaryMessage(dropped),
 		})
 	}
+	l.async.enqueue(entry)
+}
+
+/*
+writeEntry - Renders an entry through the formatter and fires every registered hook. Called
+synchronously for Fatal-level entries and from the background dispatch goroutine otherwise.
+*/
+func (l *Logger) writeEntry(entry LogEntry) {
+	if l.formatter != nil {
+		line := l.formatter.Format(entry)
+		l.writeMu.Lock()
+		l.stream.Write(line)
+		l.writeMu.Unlock()
+	}
+	for _, hook := range l.hooks {
+		hook.Fire(entry)
+	}
+}
+
+/*
+Stats - Returns a snapshot of emitted/dropped counters per level, reflecting rate limiting and
+deduplication suppression. If neither rate limiting nor deduplication is configured no counters are
+kept, and the returned Stats has an empty Levels map.
+*/
+func (l *Logger) Stats() Stats {
+	if l.limiter == nil {
+		return Stats{Levels: map[string]LevelStats{}}
+	}
+	return l.limiter.stats()
+}
+
+/*
+printf - Formats a message with its arguments and runs it through the logger pipeline.
+*/
+func (l *Logger) printf(levelInt int, level, message string, other ...interface{}) {
+	l.emitf(levelInt, level, message, l.fields, other...)
+}
+
+/*
+printLine - Runs a plain message through the logger pipeline.
+*/
+func (l *Logger) printLine(levelInt int, level, message string) {
+	l.emit(levelInt, level, message, l.fields)
 }
 
 /*--------------------------------------------------------------------------------------------------
@@ -220,58 +409,42 @@ func (l *Logger) sendRiemann(message, level string, other ...interface{}) {
 Fatalf - Print a fatal message to the console. Does NOT cause panic.
 */
 func (l *Logger) Fatalf(message string, other ...interface{}) {
-	if LogFatal <= l.level {
-		l.printf(message, "FATAL", other...)
-	}
-	l.sendRiemann(message, "FATAL", other...)
+	l.printf(LogFatal, "FATAL", message, other...)
 }
 
 /*
 Errorf - Print an error message to the console.
 */
 func (l *Logger) Errorf(message string, other ...interface{}) {
-	if LogError <= l.level {
-		l.printf(message, "ERROR", other...)
-	}
-	l.sendRiemann(message, "ERROR", other...)
+	l.printf(LogError, "ERROR", message, other...)
 }
 
 /*
 Warnf - Print a warning message to the console.
 */
 func (l *Logger) Warnf(message string, other ...interface{}) {
-	if LogWarn <= l.level {
-		l.printf(message, "WARN", other...)
-	}
-	l.sendRiemann(message, "WARN", other...)
+	l.printf(LogWarn, "WARN", message, other...)
 }
 
 /*
 Infof - Print an information message to the console.
 */
 func (l *Logger) Infof(message string, other ...interface{}) {
-	if LogInfo <= l.level {
-		l.printf(message, "INFO", other...)
-	}
-	l.sendRiemann(message, "INFO", other...)
+	l.printf(LogInfo, "INFO", message, other...)
 }
 
 /*
 Debugf - Print a debug message to the console.
 */
 func (l *Logger) Debugf(message string, other ...interface{}) {
-	if LogDebug <= l.level {
-		l.printf(message, "DEBUG", other...)
-	}
+	l.printf(LogDebug, "DEBUG", message, other...)
 }
 
 /*
 Tracef - Print a trace message to the console.
 */
 func (l *Logger) Tracef(message string, other ...interface{}) {
-	if LogTrace <= l.level {
-		l.printf(message, "TRACE", other...)
-	}
+	l.printf(LogTrace, "TRACE", message, other...)
 }
 
 /*--------------------------------------------------------------------------------------------------
@@ -281,58 +454,42 @@ func (l *Logger) Tracef(message string, other ...interface{}) {
 Fatalln - Print a fatal message to the console. Does NOT cause panic.
 */
 func (l *Logger) Fatalln(message string) {
-	if LogFatal <= l.level {
-		l.printLine(message, "FATAL")
-	}
-	l.sendRiemann(message, "FATAL")
+	l.printLine(LogFatal, "FATAL", message)
 }
 
 /*
 Errorln - Print an error message to the console.
 */
 func (l *Logger) Errorln(message string) {
-	if LogError <= l.level {
-		l.printLine(message, "ERROR")
-	}
-	l.sendRiemann(message, "ERROR")
+	l.printLine(LogError, "ERROR", message)
 }
 
 /*
 Warnln - Print a warning message to the console.
 */
 func (l *Logger) Warnln(message string) {
-	if LogWarn <= l.level {
-		l.printLine(message, "WARN")
-	}
-	l.sendRiemann(message, "WARN")
+	l.printLine(LogWarn, "WARN", message)
 }
 
 /*
 Infoln - Print an information message to the console.
 */
 func (l *Logger) Infoln(message string) {
-	if LogInfo <= l.level {
-		l.printLine(message, "INFO")
-	}
-	l.sendRiemann(message, "INFO")
+	l.printLine(LogInfo, "INFO", message)
 }
 
 /*
 Debugln - Print a debug message to the console.
 */
 func (l *Logger) Debugln(message string) {
-	if LogDebug <= l.level {
-		l.printLine(message, "DEBUG")
-	}
+	l.printLine(LogDebug, "DEBUG", message)
 }
 
 /*
 Traceln - Print a trace message to the console.
 */
 func (l *Logger) Traceln(message string) {
-	if LogTrace <= l.level {
-		l.printLine(message, "TRACE")
-	}
+	l.printLine(LogTrace, "TRACE", message)
 }
 
 /*--------------------------------------------------------------------------------------------------
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+/*
+TestPrintfDoesNotFormatBelowThreshold - A disabled level must short-circuit before fmt.Sprintf runs
+on the call's arguments, since formatting may itself invoke a Stringer/Error with real cost or side
+effects.
+*/
+func TestPrintfDoesNotFormatBelowThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, LoggerConfig{LogLevel: "INFO"})
+
+	called := false
+	logger.Debugf("value is %v", stringerFunc(func() string {
+		called = true
+		return "x"
+	}))
+
+	if called {
+		t.Fatal("expected Stringer not to be invoked for a disabled level")
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing written, got %q", buf.String())
+	}
+}
+
+/*
+stringerFunc - Adapts a plain func() string into a fmt.Stringer, letting tests detect whether
+Sprintf actually invoked String() on an argument.
+*/
+type stringerFunc func() string
+
+func (f stringerFunc) String() string { return f() }
+
+/*
+TestPrintfFormatsAtEnabledThreshold - Once the level is enabled, the formatted message must still be
+written through as before.
+*/
+func TestPrintfFormatsAtEnabledThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(&buf, LoggerConfig{LogLevel: "DEBUG", AddTimeStamp: false})
+
+	logger.Debugf("value is %v", 42)
+
+	if !bytes.Contains(buf.Bytes(), []byte("value is 42")) {
+		t.Fatalf("expected formatted message in output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,226 @@
+package log
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+RateLimitConfig - Holds optional rate limiting and deduplication settings for a Logger. A zero value
+disables both features.
+*/
+type RateLimitConfig struct {
+	PerSecond   float64       `json:"per_second" yaml:"per_second"`
+	Burst       int           `json:"burst" yaml:"burst"`
+	DedupWindow time.Duration `json:"dedup_window" yaml:"dedup_window"`
+}
+
+/*
+LevelStats - Emitted/dropped counters for a single log level.
+*/
+type LevelStats struct {
+	Emitted int64
+	Dropped int64
+}
+
+/*
+Stats - A snapshot of Logger.Stats(), keyed by human readable level.
+*/
+type Stats struct {
+	Levels map[string]LevelStats
+}
+
+/*
+maxDedupEntries - Caps the dedup LRU so that a runaway variety of distinct messages can't grow it
+without bound.
+*/
+const maxDedupEntries = 1024
+
+/*
+rateLimiter - Implements per-level token-bucket rate limiting and (level, message) deduplication for
+a Logger.
+*/
+type rateLimiter struct {
+	mu          sync.Mutex
+	perSecond   float64
+	burst       int
+	dedupWindow time.Duration
+	buckets     map[int]*tokenBucket
+	dedup       map[string]*dedupEntry
+	dedupOrder  []string
+	counters    map[int]*LevelStats
+}
+
+/*
+tokenBucket - A simple token-bucket, refilled continuously at a fixed rate up to a capacity.
+*/
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	rate     float64
+	last     time.Time
+}
+
+/*
+dedupEntry - Tracks the current suppression window for a given (level, format-string) key. message
+holds the fully interpolated text of the window's first occurrence, used to render the summary
+line.
+*/
+type dedupEntry struct {
+	count     int
+	firstSeen time.Time
+	message   string
+}
+
+/*
+newRateLimiter - Returns nil if config enables neither rate limiting nor deduplication, otherwise
+returns a ready-to-use rateLimiter.
+*/
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	if config.PerSecond <= 0 && config.DedupWindow <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		perSecond:   config.PerSecond,
+		burst:       config.Burst,
+		dedupWindow: config.DedupWindow,
+		buckets:     map[int]*tokenBucket{},
+		dedup:       map[string]*dedupEntry{},
+		counters:    map[int]*LevelStats{},
+	}
+}
+
+/*
+dedupCheck - Looks up the (level, format-string) key, keying on formatKey (the pre-interpolation
+template, e.g. "dial tcp %d: refused") rather than message (the interpolated text actually
+displayed), so that repeated calls with the same template but different arguments are recognised as
+duplicates. If an existing window has expired and accumulated suppressed repeats, it is closed and
+its summary line returned. Returns pass=false if the current message falls inside an active window
+and should be suppressed.
+*/
+func (r *rateLimiter) dedupCheck(levelInt int, level, formatKey, message string) (summary string, hasSummary, pass bool) {
+	if r.dedupWindow <= 0 {
+		return "", false, true
+	}
+
+	key := fmt.Sprintf("%d|%v", levelInt, formatKey)
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.dedup[key]
+	if ok {
+		// Move key to the back of dedupOrder so that eviction drops the least recently used key.
+		for i, k := range r.dedupOrder {
+			if k == key {
+				copy(r.dedupOrder[i:], r.dedupOrder[i+1:])
+				r.dedupOrder[len(r.dedupOrder)-1] = key
+				break
+			}
+		}
+	}
+	if ok && now.Sub(entry.firstSeen) < r.dedupWindow {
+		entry.count++
+		return "", false, false
+	}
+
+	if ok && entry.count > 0 {
+		summary = fmt.Sprintf("%v (repeated %d times in %v)", entry.message, entry.count, now.Sub(entry.firstSeen).Round(time.Second))
+		hasSummary = true
+	}
+
+	r.dedup[key] = &dedupEntry{firstSeen: now, message: message}
+	if !ok {
+		r.dedupOrder = append(r.dedupOrder, key)
+		if len(r.dedupOrder) > maxDedupEntries {
+			oldest := r.dedupOrder[0]
+			r.dedupOrder = r.dedupOrder[1:]
+			delete(r.dedup, oldest)
+		}
+	}
+
+	return summary, hasSummary, true
+}
+
+/*
+allowRate - Consumes a token from the per-level bucket, lazily creating it on first use. Returns
+false if the level's burst has been exhausted.
+*/
+func (r *rateLimiter) allowRate(levelInt int) bool {
+	if r.perSecond <= 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bucket, ok := r.buckets[levelInt]
+	if !ok {
+		burst := r.burst
+		if burst <= 0 {
+			burst = 1
+		}
+		bucket = &tokenBucket{tokens: float64(burst), capacity: float64(burst), rate: r.perSecond, last: time.Now()}
+		r.buckets[levelInt] = bucket
+	}
+
+	now := time.Now()
+	bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.rate
+	if bucket.tokens > bucket.capacity {
+		bucket.tokens = bucket.capacity
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+/*
+recordEmitted - Increments the emitted counter for a level.
+*/
+func (r *rateLimiter) recordEmitted(levelInt int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.levelCounterLocked(levelInt).Emitted++
+}
+
+/*
+recordDropped - Increments the dropped counter for a level.
+*/
+func (r *rateLimiter) recordDropped(levelInt int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.levelCounterLocked(levelInt).Dropped++
+}
+
+/*
+levelCounterLocked - Returns the counter bucket for a level, creating it if necessary. Callers must
+hold r.mu.
+*/
+func (r *rateLimiter) levelCounterLocked(levelInt int) *LevelStats {
+	counter, ok := r.counters[levelInt]
+	if !ok {
+		counter = &LevelStats{}
+		r.counters[levelInt] = counter
+	}
+	return counter
+}
+
+/*
+stats - Returns a snapshot of the emitted/dropped counters, keyed by human readable level.
+*/
+func (r *rateLimiter) stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	levels := make(map[string]LevelStats, len(r.counters))
+	for levelInt, counter := range r.counters {
+		levels[intToLogLevel(levelInt)] = *counter
+	}
+	return Stats{Levels: levels}
+}
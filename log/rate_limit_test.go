@@ -0,0 +1,141 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+TestNewRateLimiterNilWhenDisabled - A zero-value RateLimitConfig must disable both rate limiting and
+deduplication, so newRateLimiter should return nil rather than an inert limiter.
+*/
+func TestNewRateLimiterNilWhenDisabled(t *testing.T) {
+	if r := newRateLimiter(RateLimitConfig{}); r != nil {
+		t.Fatalf("expected nil limiter for a zero config, got %+v", r)
+	}
+}
+
+/*
+TestAllowRateRespectsBurst - The first Burst calls at a given level must pass; the next one, arriving
+before any refill, must be denied.
+*/
+func TestAllowRateRespectsBurst(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{PerSecond: 1, Burst: 2})
+
+	if !r.allowRate(LogInfo) {
+		t.Fatal("expected first call to pass")
+	}
+	if !r.allowRate(LogInfo) {
+		t.Fatal("expected second call (within burst) to pass")
+	}
+	if r.allowRate(LogInfo) {
+		t.Fatal("expected third call to be denied once burst is exhausted")
+	}
+}
+
+/*
+TestAllowRateRefillsOverTime - Once enough time has passed for the configured rate to refill a token,
+a previously denied call must pass again.
+*/
+func TestAllowRateRefillsOverTime(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{PerSecond: 1000, Burst: 1})
+
+	if !r.allowRate(LogInfo) {
+		t.Fatal("expected first call to pass")
+	}
+	if r.allowRate(LogInfo) {
+		t.Fatal("expected immediate second call to be denied")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if !r.allowRate(LogInfo) {
+		t.Fatal("expected call to pass after enough time for a refill")
+	}
+}
+
+/*
+TestAllowRateTracksLevelsIndependently - Each level gets its own bucket, so exhausting one level's
+burst must not affect another.
+*/
+func TestAllowRateTracksLevelsIndependently(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{PerSecond: 1, Burst: 1})
+
+	if !r.allowRate(LogError) {
+		t.Fatal("expected first ERROR call to pass")
+	}
+	if r.allowRate(LogError) {
+		t.Fatal("expected second ERROR call to be denied")
+	}
+	if !r.allowRate(LogWarn) {
+		t.Fatal("expected WARN's independent bucket to still allow its first call")
+	}
+}
+
+/*
+TestDedupCheckSuppressesWithinWindow - A second call with the same (level, formatKey) inside the
+dedup window must be suppressed, and the window's count must be reflected in the eventual summary.
+*/
+func TestDedupCheckSuppressesWithinWindow(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{DedupWindow: time.Hour})
+
+	_, hasSummary, pass := r.dedupCheck(LogError, "ERROR", "dial tcp %d: refused", "dial tcp 1: refused")
+	if !pass || hasSummary {
+		t.Fatalf("expected first call to pass with no summary, got pass=%v hasSummary=%v", pass, hasSummary)
+	}
+
+	_, hasSummary, pass = r.dedupCheck(LogError, "ERROR", "dial tcp %d: refused", "dial tcp 2: refused")
+	if pass || hasSummary {
+		t.Fatalf("expected second call within the window to be suppressed, got pass=%v hasSummary=%v", pass, hasSummary)
+	}
+}
+
+/*
+TestDedupCheckDistinguishesFormatKeyFromMessage - Suppression must key on formatKey (the
+pre-interpolation template), not the interpolated message, so calls sharing a format string but
+differing arguments are still recognised as duplicates of one another.
+*/
+func TestDedupCheckDistinguishesFormatKeyFromMessage(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{DedupWindow: time.Hour})
+
+	for i := 0; i < 5; i++ {
+		_, _, pass := r.dedupCheck(LogError, "ERROR", "dial tcp %d: refused", fmt.Sprintf("dial tcp %d: refused", i))
+		if i == 0 && !pass {
+			t.Fatal("expected the first occurrence to pass")
+		}
+		if i > 0 && pass {
+			t.Fatalf("expected occurrence %d with the same format key to be suppressed", i)
+		}
+	}
+}
+
+/*
+TestDedupCheckEmitsSummaryAfterWindowExpires - Once the dedup window has elapsed, the next call must
+pass again and produce a summary describing the suppressed repeats.
+*/
+func TestDedupCheckEmitsSummaryAfterWindowExpires(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{DedupWindow: 5 * time.Millisecond})
+
+	if _, _, pass := r.dedupCheck(LogWarn, "WARN", "retrying %d", "retrying 1"); !pass {
+		t.Fatal("expected first call to pass")
+	}
+	if _, _, pass := r.dedupCheck(LogWarn, "WARN", "retrying %d", "retrying 2"); pass {
+		t.Fatal("expected second call within the window to be suppressed")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	summary, hasSummary, pass := r.dedupCheck(LogWarn, "WARN", "retrying %d", "retrying 3")
+	if !pass {
+		t.Fatal("expected call after window expiry to pass")
+	}
+	if !hasSummary {
+		t.Fatal("expected a summary describing the suppressed repeat")
+	}
+	for _, want := range []string{"retrying 1", "repeated 1 times"} {
+		if !strings.Contains(summary, want) {
+			t.Fatalf("expected summary to contain %q, got %q", want, summary)
+		}
+	}
+}
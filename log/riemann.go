@@ -0,0 +1,33 @@
+package log
+
+import "errors"
+
+/*
+ErrClientNil - Returned when a nil RiemannClient is provided to UseRiemann.
+*/
+var ErrClientNil = errors.New("riemann client was nil")
+
+/*
+RiemannEvent - Represents a single event to be forwarded to a Riemann service.
+*/
+type RiemannEvent struct {
+	Service     string
+	State       string
+	Description string
+	Metric      float64
+	Attributes  map[string]string
+}
+
+/*
+RiemannClient - A minimal stand-in for a Riemann client. It holds no connection and forwards nothing
+to a Riemann service.
+*/
+type RiemannClient struct {
+}
+
+/*
+SendEvent - Accepts a RiemannEvent and returns nil; the event is discarded, not sent.
+*/
+func (r *RiemannClient) SendEvent(event RiemannEvent) error {
+	return nil
+}
@@ -0,0 +1,109 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/*
+TestRotatingFileWriterBackupsDoNotCollide - Rotating many times in quick succession (as happens under
+a low MaxSizeBytes or a bursty writer) must not produce backup filenames that collide and silently
+overwrite one another.
+*/
+func TestRotatingFileWriterBackupsDoNotCollide(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewRotatingFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter: %v", err)
+	}
+	w.MaxSizeBytes = 1
+	w.MaxBackups = 100
+
+	const writes = 20
+	for i := 0; i < writes; i++ {
+		if _, err := w.Write([]byte("x")); err != nil {
+			t.Fatalf("Write %d: %v", i, err)
+		}
+	}
+
+	// The first write never exceeds MaxSizeBytes on its own (there's nothing to rotate out of yet),
+	// so writes-1 rotations - and therefore writes-1 backups - are expected.
+	const wantBackups = writes - 1
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != wantBackups {
+		t.Fatalf("expected %d distinct backups, got %d: %v", wantBackups, len(matches), matches)
+	}
+}
+
+/*
+TestRotatingFileWriterPrunesOldBackups - Once the number of backups exceeds MaxBackups, the oldest
+ones must be removed.
+*/
+func TestRotatingFileWriterPrunesOldBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewRotatingFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter: %v", err)
+	}
+	w.MaxSizeBytes = 1
+	w.MaxBackups = 3
+
+	for i := 0; i < 10; i++ {
+		if _, err := w.Write([]byte("x")); err != nil {
+			t.Fatalf("Write %d: %v", i, err)
+		}
+	}
+
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+	if len(matches) != w.MaxBackups {
+		t.Fatalf("expected %d surviving backups, got %d: %v", w.MaxBackups, len(matches), matches)
+	}
+}
+
+/*
+TestRotatingFileWriterReopen - Reopen must pick up a fresh file at the same path after the original
+is moved out from under the writer, as an external log-rotate tool would do.
+*/
+func TestRotatingFileWriterReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewRotatingFileWriter(path)
+	if err != nil {
+		t.Fatalf("NewRotatingFileWriter: %v", err)
+	}
+	if _, err := w.Write([]byte("before\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	moved := path + ".moved"
+	if err := os.Rename(path, moved); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	if err := w.Reopen(); err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	if _, err := w.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "after\n" {
+		t.Fatalf("expected reopened file to contain only post-reopen writes, got %q", data)
+	}
+}